@@ -0,0 +1,52 @@
+// Package genac implements the rclone genac command
+package genac
+
+import (
+	"os"
+
+	"github.com/rclone/rclone/cmd"
+	"github.com/rclone/rclone/fs"
+	"github.com/rclone/rclone/fs/config/configflags"
+	"github.com/spf13/cobra"
+)
+
+var (
+	shellFlag    string
+	outputFlag   string
+	providerFlag string
+)
+
+func init() {
+	cmd.Root.AddCommand(commandDefinition)
+	flags := commandDefinition.Flags()
+	flags.StringVar(&shellFlag, "shell", "bash", "Shell dialect to generate a completion script for (bash, zsh, fish, powershell)")
+	flags.StringVar(&outputFlag, "output", "", "File to write the completion script to (default: stdout)")
+	flags.StringVar(&providerFlag, "provider", "", "Only include options that apply to this provider")
+	// --config-file is a global flag (it affects every backend, not
+	// just genac), so it belongs on the persistent flag set rather
+	// than this command's own.
+	configflags.AddFlags(cmd.Root.PersistentFlags())
+}
+
+var commandDefinition = &cobra.Command{
+	Use:   "genac",
+	Short: `Generate shell completion scripts for rclone.`,
+	Long: `This generates shell completion scripts covering every backend prefix
+and option flag known to the Registry, for use with ` + "`" + `rclone genac --shell zsh --output _rclone` + "`" + ` or similar.`,
+	Annotations: map[string]string{
+		"versionIntroduced": "v1.71",
+	},
+	RunE: func(command *cobra.Command, args []string) error {
+		cmd.CheckArgs(0, 0, command, args)
+		w := os.Stdout
+		if outputFlag != "" {
+			f, err := os.Create(outputFlag)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			return fs.GenCompletion(fs.CompletionShell(shellFlag), providerFlag, f)
+		}
+		return fs.GenCompletion(fs.CompletionShell(shellFlag), providerFlag, w)
+	},
+}
@@ -0,0 +1,208 @@
+package fs
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// CompletionShell identifies a shell dialect supported by GenCompletion
+type CompletionShell string
+
+// Supported CompletionShell values
+const (
+	CompletionShellBash       CompletionShell = "bash"
+	CompletionShellZsh        CompletionShell = "zsh"
+	CompletionShellFish       CompletionShell = "fish"
+	CompletionShellPowershell CompletionShell = "powershell"
+)
+
+// completionCandidate is one thing a shell can offer to complete,
+// either a backend prefix (e.g. "s3:") or an option flag.
+type completionCandidate struct {
+	Name string // the text to complete, e.g. "--s3-region" or "s3:"
+	Help string
+}
+
+// completionCandidates walks the Registry and returns every backend
+// prefix and option flag that should be offered for completion.
+//
+// providerFilter restricts the result to options that apply to that
+// provider (via Option.Provider, same matching rules as the
+// configurator), plus every option with no Provider restriction at
+// all. An empty providerFilter offers every option unfiltered.
+// OptionExamples are included as extra candidates for option values
+// when Exclusive is set.
+func completionCandidates(providerFilter string) (prefixes []completionCandidate, flags []completionCandidate) {
+	seenPrefix := map[string]bool{}
+	seenFlag := map[string]bool{}
+	for _, info := range Registry {
+		if info.Hide {
+			continue
+		}
+		if !seenPrefix[info.Prefix] {
+			seenPrefix[info.Prefix] = true
+			prefixes = append(prefixes, completionCandidate{
+				Name: info.Prefix + ":",
+				Help: info.Description,
+			})
+		}
+		for i := range info.Options {
+			opt := &info.Options[i]
+			if opt.Hide&OptionHideCommandLine != 0 {
+				continue
+			}
+			if opt.Provider != "" && !matchProvider(opt.Provider, providerFilter) {
+				continue
+			}
+			flagName := "--" + opt.FlagName(info.Prefix)
+			if seenFlag[flagName] {
+				continue
+			}
+			seenFlag[flagName] = true
+			flags = append(flags, completionCandidate{
+				Name: flagName,
+				Help: opt.Help,
+			})
+			if opt.Exclusive {
+				for _, example := range opt.Examples {
+					flags = append(flags, completionCandidate{
+						Name: flagName + "=" + example.Value,
+						Help: example.Help,
+					})
+				}
+			}
+		}
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return prefixes[i].Name < prefixes[j].Name })
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Name < flags[j].Name })
+	return prefixes, flags
+}
+
+// GenCompletion writes a shell completion script for shell to w,
+// covering every visible backend prefix and option flag in the
+// Registry that applies to providerFilter (pass "" to offer every
+// provider's options).
+func GenCompletion(shell CompletionShell, providerFilter string, w io.Writer) error {
+	prefixes, flags := completionCandidates(providerFilter)
+	switch shell {
+	case CompletionShellBash:
+		return genCompletionBash(w, prefixes, flags)
+	case CompletionShellZsh:
+		return genCompletionZsh(w, prefixes, flags)
+	case CompletionShellFish:
+		return genCompletionFish(w, prefixes, flags)
+	case CompletionShellPowershell:
+		return genCompletionPowershell(w, prefixes, flags)
+	}
+	return fmt.Errorf("unknown shell %q for completion generation", shell)
+}
+
+func genCompletionBash(w io.Writer, prefixes, flags []completionCandidate) error {
+	var words []string
+	for _, c := range prefixes {
+		words = append(words, c.Name)
+	}
+	for _, c := range flags {
+		words = append(words, c.Name)
+	}
+	_, err := fmt.Fprintf(w, "complete -W %q rclone\n", strings.Join(words, " "))
+	return err
+}
+
+func genCompletionZsh(w io.Writer, prefixes, flags []completionCandidate) error {
+	if _, err := fmt.Fprintln(w, "#compdef rclone"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "_rclone_remotes() {"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "  local -a remotes"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "  remotes=("); err != nil {
+		return err
+	}
+	for _, c := range prefixes {
+		if _, err := fmt.Fprintf(w, "    %q\n", fmt.Sprintf("%s:%s", c.Name, c.Help)); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintln(w, "  )"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "  _describe 'remote' remotes"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "}"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "_rclone_flags() {"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "  local -a opts"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "  opts=("); err != nil {
+		return err
+	}
+	for _, c := range flags {
+		if _, err := fmt.Fprintf(w, "    %q\n", fmt.Sprintf("%s[%s]", c.Name, c.Help)); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintln(w, "  )"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "  _describe 'option' opts"); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+func genCompletionFish(w io.Writer, prefixes, flags []completionCandidate) error {
+	for _, c := range prefixes {
+		if _, err := fmt.Fprintf(w, "complete -c rclone -a %q -d %q\n", c.Name, c.Help); err != nil {
+			return err
+		}
+	}
+	for _, c := range flags {
+		if _, err := fmt.Fprintf(w, "complete -c rclone -l %q -d %q\n", strings.TrimPrefix(c.Name, "--"), c.Help); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func genCompletionPowershell(w io.Writer, prefixes, flags []completionCandidate) error {
+	_, err := fmt.Fprintln(w, "Register-ArgumentCompleter -Native -CommandName rclone -ScriptBlock {")
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "  param($wordToComplete, $commandAst, $cursorPosition)"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "  $candidates = @("); err != nil {
+		return err
+	}
+	for _, c := range prefixes {
+		if _, err := fmt.Fprintf(w, "    %q\n", c.Name); err != nil {
+			return err
+		}
+	}
+	for _, c := range flags {
+		if _, err := fmt.Fprintf(w, "    %q\n", c.Name); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintln(w, "  )"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "  $candidates | Where-Object { $_ -like \"$wordToComplete*\" }"); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, "}")
+	return err
+}
@@ -0,0 +1,80 @@
+package fs
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden completion files in testdata/completion")
+
+// withFixedRegistry replaces the global Registry with a small, fixed
+// set of backends for the duration of the test, so the golden files
+// below don't churn every time a real backend gains an option.
+func withFixedRegistry(t *testing.T) {
+	t.Helper()
+	saved := Registry
+	Registry = nil
+	t.Cleanup(func() { Registry = saved })
+
+	Registry = append(Registry, &RegInfo{
+		Name:        "local",
+		Prefix:      "local",
+		Description: "Local Disk",
+	})
+	Registry = append(Registry, &RegInfo{
+		Name:        "s3",
+		Prefix:      "s3",
+		Description: "Amazon S3 Compliant Storage",
+		Options: Options{
+			{Name: "region", Help: "Region to connect to.", Provider: "AWS"},
+			{Name: "endpoint", Help: "Endpoint for Ceph.", Provider: "Ceph"},
+		},
+	})
+}
+
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "completion", name)
+	if *updateGolden {
+		require.NoError(t, os.WriteFile(path, got, 0644))
+	}
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestGenCompletionGolden(t *testing.T) {
+	withFixedRegistry(t)
+
+	for _, test := range []struct {
+		shell CompletionShell
+		file  string
+	}{
+		{CompletionShellBash, "bash.golden"},
+		{CompletionShellZsh, "zsh.golden"},
+		{CompletionShellFish, "fish.golden"},
+		{CompletionShellPowershell, "powershell.golden"},
+	} {
+		t.Run(string(test.shell), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, GenCompletion(test.shell, "", &buf))
+			checkGolden(t, test.file, buf.Bytes())
+		})
+	}
+}
+
+func TestGenCompletionProviderFilter(t *testing.T) {
+	withFixedRegistry(t)
+
+	var buf bytes.Buffer
+	require.NoError(t, GenCompletion(CompletionShellFish, "AWS", &buf))
+	out := buf.String()
+	assert.Contains(t, out, "--s3-region", "AWS-provider option should be offered")
+	assert.NotContains(t, out, "--s3-endpoint", "Ceph-only option should be filtered out for the AWS provider")
+}
@@ -0,0 +1,13 @@
+// Package configflags defines the command line flags for the config
+// file backend
+package configflags
+
+import (
+	"github.com/rclone/rclone/fs"
+	"github.com/spf13/pflag"
+)
+
+// AddFlags adds the flags for this package to flagSet
+func AddFlags(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(&fs.ConfigFilePath, "config-file", fs.ConfigFilePath, "Path to a JSON file supplying layered remote and global option values (.yaml, .yml and .toml files are rejected)")
+}
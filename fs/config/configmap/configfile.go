@@ -0,0 +1,86 @@
+package configmap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileGetter is a Getter which reads option values out of a
+// structured config file.
+//
+// The file is expected to be keyed by remote name, e.g.
+//
+//	{
+//	  "my-s3": {"region": "eu-west-1", "storage_class": "GLACIER"},
+//	  "*":     {"transfers": "16"}
+//	}
+//
+// The special remote name "*" supplies global options that apply to
+// every remote unless overridden more specifically in the same file.
+//
+// Only JSON is implemented so far. YAML and TOML are intended to
+// decode to the same map[string]map[string]string shape and are
+// planned, but LoadFileGetter rejects them for now rather than
+// silently mis-parsing them as JSON.
+type FileGetter struct {
+	path   string
+	remote string
+	values map[string]map[string]string
+}
+
+// LoadFileGetter reads path and returns a FileGetter scoped to
+// remote. Values under the "*" key are used as a fallback for any
+// option not set under remote.
+//
+// The file format is chosen from path's extension. Only ".json" is
+// currently supported; ".yaml", ".yml" and ".toml" return an error
+// until a decoder for them is added.
+func LoadFileGetter(path, remote string) (*FileGetter, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		// supported below
+	case ".yaml", ".yml", ".toml":
+		return nil, fmt.Errorf("config file %q: %s format is not implemented yet - use .json for now", path, ext)
+	default:
+		return nil, fmt.Errorf("config file %q: unrecognised extension %q", path, ext)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %q: %w", path, err)
+	}
+	values := map[string]map[string]string{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %q: %w", path, err)
+	}
+	return &FileGetter{
+		path:   path,
+		remote: remote,
+		values: values,
+	}, nil
+}
+
+// Path returns the file this FileGetter was loaded from, for
+// diagnostics in `rclone config redacted` and `--dump config`.
+func (f *FileGetter) Path() string {
+	return f.path
+}
+
+// Get an option value from the file, checking the remote-specific
+// section first and falling back to the "*" global section.
+func (f *FileGetter) Get(key string) (value string, ok bool) {
+	if remoteValues, found := f.values[f.remote]; found {
+		if value, ok = remoteValues[key]; ok {
+			return value, true
+		}
+	}
+	if globalValues, found := f.values["*"]; found {
+		if value, ok = globalValues[key]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,51 @@
+package configmap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestConfigFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	return path
+}
+
+func TestLoadFileGetterJSON(t *testing.T) {
+	path := writeTestConfigFile(t, "remotes.json", `{
+		"my-s3": {"region": "eu-west-1"},
+		"*": {"transfers": "16", "region": "us-east-1"}
+	}`)
+
+	g, err := LoadFileGetter(path, "my-s3")
+	require.NoError(t, err)
+	assert.Equal(t, path, g.Path())
+
+	value, ok := g.Get("region")
+	assert.True(t, ok)
+	assert.Equal(t, "eu-west-1", value, "remote-specific value takes precedence over the \"*\" global section")
+
+	value, ok = g.Get("transfers")
+	assert.True(t, ok)
+	assert.Equal(t, "16", value, "falls back to the \"*\" global section when the remote has no value")
+
+	_, ok = g.Get("does_not_exist")
+	assert.False(t, ok)
+}
+
+func TestLoadFileGetterUnsupportedFormats(t *testing.T) {
+	for _, ext := range []string{".yaml", ".yml", ".toml"} {
+		path := writeTestConfigFile(t, "remotes"+ext, "my-s3:\n  region: eu-west-1\n")
+		_, err := LoadFileGetter(path, "my-s3")
+		require.Error(t, err, "%s is not implemented yet and must fail loudly, not mis-parse as JSON", ext)
+	}
+
+	path := writeTestConfigFile(t, "remotes.ini", "[my-s3]\nregion=eu-west-1\n")
+	_, err := LoadFileGetter(path, "my-s3")
+	require.Error(t, err)
+}
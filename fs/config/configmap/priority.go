@@ -0,0 +1,19 @@
+package configmap
+
+// Additional Priority layers consulted when resolving backend options
+// from flags, environment variables and a config file, on top of the
+// pre-existing PriorityNormal (the remote's own stored config).
+//
+// These are anchored directly off PriorityNormal, one below it each,
+// rather than an arbitrary offset, so that existing callers such as
+// Options.Overridden - which caps its lookup at
+// GetPriority(name, PriorityNormal) - continue to see values set at
+// these layers instead of having them silently excluded by the cap.
+//
+// See fs.Options.OverriddenWithSource for how these are consulted to
+// report provenance.
+const (
+	PriorityConfigFile Priority = PriorityNormal - 1
+	PriorityEnv        Priority = PriorityNormal - 2
+	PriorityFlag       Priority = PriorityNormal - 3
+)
@@ -0,0 +1,52 @@
+package fs
+
+import (
+	"context"
+
+	"github.com/rclone/rclone/fs/config/configmap"
+)
+
+// ConfigFilePath is the path to a JSON file supplying layered remote
+// and global option values, set by the --config-file flag (see
+// fs/config/configflags). Empty means no config file layer is used.
+// Only .json files are read: configmap.LoadFileGetter returns an
+// error for .yaml, .yml and .toml paths.
+var ConfigFilePath string
+
+// PushConfigFileLayer loads ConfigFilePath, if set, and adds it to m
+// as a configmap.Getter at configmap.PriorityConfigFile, so Option
+// values it supplies for remote take effect with lower precedence
+// than an explicit flag or environment variable but higher than the
+// remote's own stored config.
+//
+// It is a no-op if ConfigFilePath is empty.
+func PushConfigFileLayer(m *configmap.Map, remote string) error {
+	if ConfigFilePath == "" {
+		return nil
+	}
+	getter, err := configmap.LoadFileGetter(ConfigFilePath, remote)
+	if err != nil {
+		return err
+	}
+	m.AddGetter(getter, configmap.PriorityConfigFile)
+	return nil
+}
+
+// wrapNewFsWithConfigFile wraps newFs so that, before it runs, the
+// config file layer configured via ConfigFilePath is pushed onto the
+// *configmap.Map used to create the remote. This is the actual
+// config-loading path: every backend's NewFs is invoked through this
+// wrapper once registered.
+func wrapNewFsWithConfigFile(newFs func(ctx context.Context, name, root string, config configmap.Mapper) (Fs, error)) func(ctx context.Context, name, root string, config configmap.Mapper) (Fs, error) {
+	if newFs == nil {
+		return nil
+	}
+	return func(ctx context.Context, name, root string, config configmap.Mapper) (Fs, error) {
+		if m, ok := config.(*configmap.Map); ok {
+			if err := PushConfigFileLayer(m, name); err != nil {
+				return nil, err
+			}
+		}
+		return newFs(ctx, name, root, config)
+	}
+}
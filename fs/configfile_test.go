@@ -0,0 +1,37 @@
+package fs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushConfigFileLayerNoop(t *testing.T) {
+	saved := ConfigFilePath
+	ConfigFilePath = ""
+	defer func() { ConfigFilePath = saved }()
+
+	require.NoError(t, PushConfigFileLayer(nil, "my-remote"), "empty ConfigFilePath must be a no-op, even with a nil Map")
+}
+
+func TestWrapNewFsWithConfigFileSkipsNonMap(t *testing.T) {
+	saved := ConfigFilePath
+	ConfigFilePath = ""
+	defer func() { ConfigFilePath = saved }()
+
+	called := false
+	wrapped := wrapNewFsWithConfigFile(func(ctx context.Context, name, root string, config configmap.Mapper) (Fs, error) {
+		called = true
+		return nil, nil
+	})
+	_, err := wrapped(context.Background(), "my-remote", "", configmap.Simple{})
+	require.NoError(t, err)
+	assert.True(t, called, "the wrapped NewFs must still run when config isn't a *configmap.Map")
+}
+
+func TestWrapNewFsWithConfigFileNil(t *testing.T) {
+	assert.Nil(t, wrapNewFsWithConfigFile(nil), "wrapping a nil NewFs (a backend with no NewFs) must stay nil")
+}
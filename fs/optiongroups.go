@@ -0,0 +1,155 @@
+package fs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GroupInfo describes one node in the Option.Groups hierarchy, used
+// by the configurator and the rc `options/info` endpoint to render
+// tabbed/nested UIs instead of one flat list.
+type GroupInfo struct {
+	Name        string // matches a value in the comma separated Option.Groups
+	DisplayName string // human readable name shown in the UI
+	Parent      string // name of the parent group, or "" for a top level group
+	Order       int    // lower sorts first among siblings
+}
+
+// AdvancedGroupName is the group auto-derived by Register for
+// options with Advanced set, so they are grouped together even when
+// a backend doesn't declare its own group hierarchy.
+const AdvancedGroupName = "Advanced"
+
+// groupRegistry holds the known GroupInfo entries, keyed by Name
+var groupRegistry = map[string]*GroupInfo{
+	AdvancedGroupName: {Name: AdvancedGroupName, DisplayName: "Advanced", Order: 1 << 30},
+}
+
+// RegisterGroup adds info to the group registry so it can be
+// referenced from an Option.Groups tag. It is idempotent: calling it
+// again with the same Name updates the existing entry.
+func RegisterGroup(info GroupInfo) {
+	groupRegistry[info.Name] = &info
+}
+
+// FindGroup looks up a registered GroupInfo by name
+func FindGroup(name string) (*GroupInfo, bool) {
+	info, ok := groupRegistry[name]
+	return info, ok
+}
+
+// groupNames splits an Option.Groups tag into its individual group names
+func groupNames(groups string) []string {
+	if groups == "" {
+		return nil
+	}
+	return splitCommaTrim(groups)
+}
+
+// splitCommaTrim splits s on commas and trims whitespace from each part
+func splitCommaTrim(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// ByGroup partitions the Options by their first declared group,
+// returning a map from group name to the Options belonging to it.
+// Options with no Groups set are returned under the empty string key.
+func (os Options) ByGroup() map[string]Options {
+	byGroup := map[string]Options{}
+	for i := range os {
+		opt := &os[i]
+		names := groupNames(opt.Groups)
+		if len(names) == 0 {
+			byGroup[""] = append(byGroup[""], *opt)
+			continue
+		}
+		byGroup[names[0]] = append(byGroup[names[0]], *opt)
+	}
+	return byGroup
+}
+
+// Filter returns the subset of Options that match providerFilter (as
+// used elsewhere for Option.Provider), belong to groupFilter (or any
+// group if groupFilter is ""), and are visible according to
+// visibility.
+func (os Options) Filter(providerFilter, groupFilter string, visibility OptionVisibility) Options {
+	var out Options
+	for i := range os {
+		opt := &os[i]
+		if opt.Hide&visibility != 0 {
+			continue
+		}
+		if providerFilter != "" && !matchProvider(opt.Provider, providerFilter) {
+			continue
+		}
+		if groupFilter != "" {
+			found := false
+			for _, name := range groupNames(opt.Groups) {
+				if name == groupFilter {
+					found = true
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+		out = append(out, *opt)
+	}
+	return out
+}
+
+// matchProvider reports whether providerConfig, the comma separated
+// list of providers (and !negated providers) from Option.Provider,
+// matches provider.
+//
+// An empty providerConfig matches every provider. A bare "!foo" means
+// "anything but foo". Mixing positive and negative entries is not
+// supported, matching the existing ad-hoc convention used across the
+// backends.
+func matchProvider(providerConfig, provider string) bool {
+	if providerConfig == "" || provider == "" {
+		return true
+	}
+	negate := false
+	if providerConfig[0] == '!' {
+		negate = true
+		providerConfig = providerConfig[1:]
+	}
+	matched := false
+	for _, p := range splitCommaTrim(providerConfig) {
+		if p == provider {
+			matched = true
+			break
+		}
+	}
+	if negate {
+		return !matched
+	}
+	return matched
+}
+
+// validateGroups checks that every group referenced by opts.Groups
+// has been registered, and auto-derives the Advanced group for
+// options with Advanced set but no explicit Groups.
+func validateGroups(prefix string, opts Options) error {
+	for i := range opts {
+		opt := &opts[i]
+		if opt.Advanced && opt.Groups == "" {
+			opt.Groups = AdvancedGroupName
+		}
+		for _, name := range groupNames(opt.Groups) {
+			if _, ok := groupRegistry[name]; !ok {
+				return fmt.Errorf("%s: option %q refers to unknown group %q - register it with fs.RegisterGroup first", prefix, opt.Name, name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package fs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCommaTrim(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, splitCommaTrim("a, b ,c"))
+	assert.Nil(t, splitCommaTrim(""))
+	assert.Equal(t, []string{"a"}, splitCommaTrim("  a  "))
+}
+
+func TestOptionsByGroupAndFilter(t *testing.T) {
+	opts := Options{
+		{Name: "region", Groups: "Connection"},
+		{Name: "endpoint", Groups: "Connection,Advanced"},
+		{Name: "description"},
+	}
+
+	byGroup := opts.ByGroup()
+	assert.Len(t, byGroup["Connection"], 2)
+	assert.Len(t, byGroup[""], 1)
+
+	filtered := opts.Filter("", "Connection", 0)
+	require.Len(t, filtered, 2)
+	assert.Equal(t, "region", filtered[0].Name)
+}
+
+func TestMatchProvider(t *testing.T) {
+	assert.True(t, matchProvider("", "AWS"))
+	assert.True(t, matchProvider("AWS,Ceph", "AWS"))
+	assert.False(t, matchProvider("AWS,Ceph", "GCS"))
+	assert.True(t, matchProvider("!Ceph", "AWS"))
+	assert.False(t, matchProvider("!Ceph", "Ceph"))
+}
+
+func TestRegisterAutoAssignsAdvancedGroupToDescription(t *testing.T) {
+	saved := Registry
+	Registry = nil
+	t.Cleanup(func() { Registry = saved })
+
+	info := &RegInfo{Name: "testbackend"}
+	Register(info)
+
+	desc := info.Options.Get("description")
+	require.NotNil(t, desc)
+	assert.Equal(t, AdvancedGroupName, desc.Groups, "optDescription must be tagged into the Advanced group like any other advanced option")
+
+	byGroup := info.Options.ByGroup()
+	assert.Contains(t, byGroup[AdvancedGroupName], *desc)
+}
@@ -0,0 +1,124 @@
+package fs
+
+import (
+	"fmt"
+
+	"github.com/rclone/rclone/fs/config/configmap"
+)
+
+// ProviderRule is a cross-option validation rule that applies when a
+// provider is in effect, e.g. "if provider=Ceph then endpoint is
+// required and region is ignored".
+type ProviderRule struct {
+	// Requires lists option names that must have a non-empty value
+	// when this provider is selected.
+	Requires []string
+	// Ignores lists option names that are not applicable for this
+	// provider - ResolveForProvider drops them from the effective
+	// Options slice, so a Requires entry naming one of them is not
+	// checked.
+	Ignores []string
+}
+
+// ProviderSchema declares, for one named provider, which options
+// apply, their provider-specific defaults, and any ProviderRule cross
+// option validation.
+type ProviderSchema struct {
+	Name     string
+	Defaults configmap.Simple
+	Rule     ProviderRule
+}
+
+// ProviderSchemas is the set of providers a RegInfo declares, keyed
+// by ProviderSchema.Name
+type ProviderSchemas map[string]ProviderSchema
+
+// providerTokens collects every provider token referenced by opts,
+// across both positive and negated (!provider) Option.Provider
+// filters.
+func providerTokens(opts Options) []string {
+	seen := map[string]bool{}
+	var tokens []string
+	for i := range opts {
+		providerConfig := opts[i].Provider
+		if providerConfig == "" {
+			continue
+		}
+		if providerConfig[0] == '!' {
+			providerConfig = providerConfig[1:]
+		}
+		for _, token := range splitCommaTrim(providerConfig) {
+			if !seen[token] {
+				seen[token] = true
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	return tokens
+}
+
+// validateProviderSchemas checks that every provider token referenced
+// by an Option.Provider filter in opts is declared in schemas.
+func validateProviderSchemas(prefix string, opts Options, schemas ProviderSchemas) error {
+	if len(schemas) == 0 {
+		return nil
+	}
+	for _, token := range providerTokens(opts) {
+		if _, ok := schemas[token]; !ok {
+			return fmt.Errorf("%s: option refers to undeclared provider %q - add it to ProviderSchemas first", prefix, token)
+		}
+	}
+	return nil
+}
+
+// ResolveForProvider returns the effective, validated Options for the
+// provider configured in m, with that provider's ProviderSchema
+// defaults merged in and any options its ProviderRule.Ignores marks
+// as not applicable removed.
+//
+// This turns the previously implicit Option.Provider filter
+// convention into a validated schema, so the configurator can show
+// only the options relevant to the configured provider up front.
+func (ri *RegInfo) ResolveForProvider(m configmap.Mapper) (Options, error) {
+	provider, _ := m.Get("provider")
+
+	schema, ok := ri.ProviderSchemas[provider]
+	if provider != "" && !ok && len(ri.ProviderSchemas) > 0 {
+		return nil, fmt.Errorf("%s: unknown provider %q", ri.Name, provider)
+	}
+
+	ignored := map[string]bool{}
+	for _, name := range schema.Rule.Ignores {
+		ignored[name] = true
+	}
+
+	var opts Options
+	for i := range ri.Options {
+		opt := ri.Options[i]
+		if !matchProvider(opt.Provider, provider) {
+			continue
+		}
+		if ignored[opt.Name] {
+			continue
+		}
+		if def, ok := schema.Defaults.Get(opt.Name); ok {
+			opt.Default = def
+		}
+		opts = append(opts, opt)
+	}
+
+	for _, name := range schema.Rule.Requires {
+		opt := opts.Get(name)
+		if opt == nil {
+			continue
+		}
+		value, isSet := m.Get(name)
+		if !isSet || value == "" {
+			if fmt.Sprint(opt.GetValue()) == "" {
+				return nil, fmt.Errorf("%s: option %q is required when provider=%q", ri.Name, name, provider)
+			}
+		}
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,66 @@
+package fs
+
+import (
+	"testing"
+
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProviderRegInfo() *RegInfo {
+	return &RegInfo{
+		Name:   "testbackend",
+		Prefix: "testbackend",
+		Options: Options{
+			{Name: "region", Provider: "AWS,Ceph", Default: ""},
+			{Name: "endpoint", Provider: "Ceph", Default: ""},
+			{Name: "chunk_size", Default: "5M"},
+		},
+		ProviderSchemas: ProviderSchemas{
+			"AWS": {
+				Name:     "AWS",
+				Defaults: configmap.Simple{"chunk_size": "10M"},
+			},
+			"Ceph": {
+				Name: "Ceph",
+				Rule: ProviderRule{
+					Requires: []string{"endpoint"},
+					Ignores:  []string{"region"},
+				},
+			},
+		},
+	}
+}
+
+func TestResolveForProviderFiltersAndMergesDefaults(t *testing.T) {
+	info := testProviderRegInfo()
+
+	opts, err := info.ResolveForProvider(configmap.Simple{"provider": "AWS"})
+	require.NoError(t, err)
+
+	assert.NotNil(t, opts.Get("region"))
+	assert.Nil(t, opts.Get("endpoint"), "Ceph-only option must not appear for the AWS provider")
+	chunkSize := opts.Get("chunk_size")
+	require.NotNil(t, chunkSize)
+	assert.Equal(t, "10M", chunkSize.Default, "AWS schema default must override the option's own default")
+}
+
+func TestResolveForProviderIgnoresAndRequires(t *testing.T) {
+	info := testProviderRegInfo()
+
+	opts, err := info.ResolveForProvider(configmap.Simple{"provider": "Ceph", "endpoint": "https://ceph.example.com"})
+	require.NoError(t, err)
+	assert.Nil(t, opts.Get("region"), "Ceph's ProviderRule.Ignores must drop region")
+	assert.NotNil(t, opts.Get("endpoint"))
+
+	_, err = info.ResolveForProvider(configmap.Simple{"provider": "Ceph"})
+	assert.Error(t, err, "endpoint is Required for Ceph and has no value or default")
+}
+
+func TestResolveForProviderUnknownProvider(t *testing.T) {
+	info := testProviderRegInfo()
+
+	_, err := info.ResolveForProvider(configmap.Simple{"provider": "GCS"})
+	assert.Error(t, err)
+}
@@ -51,6 +51,11 @@ type RegInfo struct {
 	Hide bool
 	// MetadataInfo help about the metadata in use in this backend
 	MetadataInfo *MetadataInfo
+	// ProviderSchemas declares the providers this backend supports -
+	// see ResolveForProvider. May be left empty if the backend has no
+	// concept of providers, or only uses Option.Provider filters
+	// informally.
+	ProviderSchemas ProviderSchemas
 }
 
 // FileName returns the on disk file name for this backend
@@ -134,6 +139,65 @@ func (os Options) HasAdvanced() bool {
 	return false
 }
 
+// OptionSource identifies which layer of the configuration
+// precedence chain supplied an Option's current value.
+//
+// The layers are consulted in this order, highest precedence first:
+// explicit flag > environment variable > config file > remote config
+// > Option.Default.
+type OptionSource string
+
+// Recognised OptionSource values, highest precedence first
+const (
+	OptionSourceFlag       OptionSource = "flag"
+	OptionSourceEnv        OptionSource = "env"
+	OptionSourceConfigFile OptionSource = "config-file"
+	OptionSourceRemote     OptionSource = "remote"
+	OptionSourceDefault    OptionSource = "default"
+)
+
+// optionSourcesByPriority pairs each OptionSource with the
+// configmap.Priority layer it is pushed onto a *configmap.Map at,
+// highest precedence first. PriorityNormal is the remote's own stored
+// config, already used by Overridden above; the rest are additional
+// layers a config file loader or flag/env parser pushes onto the same
+// Map.
+var optionSourcesByPriority = []struct {
+	Source   OptionSource
+	Priority configmap.Priority
+}{
+	{OptionSourceFlag, configmap.PriorityFlag},
+	{OptionSourceEnv, configmap.PriorityEnv},
+	{OptionSourceConfigFile, configmap.PriorityConfigFile},
+	{OptionSourceRemote, configmap.PriorityNormal},
+}
+
+// OverriddenWithSource is Options.Overridden, plus a record of which
+// precedence layer (explicit flag > env var > config file > remote
+// config > Option.Default) supplied each overridden value.
+//
+// This is what lets `rclone config redacted` and `--dump config` show
+// provenance alongside the value.
+func (os Options) OverriddenWithSource(m *configmap.Map) (configmap.Simple, map[string]OptionSource) {
+	overridden := os.Overridden(m)
+	sources := make(map[string]OptionSource, len(overridden))
+	for name := range overridden {
+		sources[name] = optionSource(m, name)
+	}
+	return overridden, sources
+}
+
+// optionSource finds the highest precedence layer in m with a value
+// set for name, falling back to OptionSourceDefault if none do.
+func optionSource(m *configmap.Map, name string) OptionSource {
+	for _, layer := range optionSourcesByPriority {
+		if _, isSet := m.GetPriority(name, layer.Priority); isSet {
+			return layer.Source
+		}
+	}
+	return OptionSourceDefault
+}
+
 // OptionVisibility controls whether the options are visible in the
 // configurator or the command line.
 type OptionVisibility byte
@@ -187,17 +251,33 @@ type BaseOption Option
 // - DefaultStr - a string rendering of Default
 // - ValueStr - a string rendering of Value
 // - Type - the type of the option
+// - IsSlice, ElementType, Cardinality - set for slice-typed options
+//   so the rc/config UI can render add/remove controls
 func (o *Option) MarshalJSON() ([]byte, error) {
+	v := o.GetValue()
+	isSlice := isSliceValue(v)
+	var elementType string
+	var cardinality int
+	if isSlice {
+		elementType = reflect.TypeOf(v).Elem().Name()
+		cardinality = reflect.ValueOf(v).Len()
+	}
 	return json.Marshal(struct {
 		BaseOption
-		DefaultStr string
-		ValueStr   string
-		Type       string
+		DefaultStr  string
+		ValueStr    string
+		Type        string
+		IsSlice     bool   `json:",omitempty"`
+		ElementType string `json:",omitempty"`
+		Cardinality int    `json:",omitempty"`
 	}{
-		BaseOption: BaseOption(*o),
-		DefaultStr: fmt.Sprint(o.Default),
-		ValueStr:   o.String(),
-		Type:       o.Type(),
+		BaseOption:  BaseOption(*o),
+		DefaultStr:  fmt.Sprint(o.Default),
+		ValueStr:    o.String(),
+		Type:        o.Type(),
+		IsSlice:     isSlice,
+		ElementType: elementType,
+		Cardinality: cardinality,
 	})
 }
 
@@ -216,15 +296,16 @@ func (o *Option) GetValue() interface{} {
 // String turns Option into a string
 func (o *Option) String() string {
 	v := o.GetValue()
-	if stringArray, isStringArray := v.([]string); isStringArray {
-		// Treat empty string array as empty string
+	if isSliceValue(v) {
+		// Treat an empty slice as empty string
 		// This is to make the default value of the option help nice
-		if len(stringArray) == 0 {
+		if reflect.ValueOf(v).Len() == 0 {
 			return ""
 		}
-		// Encode string arrays as JSON
-		// The default Go encoding can't be decoded uniquely
-		buf, err := json.Marshal(stringArray)
+		// Encode all slice types (not just []string) as JSON so that
+		// Option.Set can read them back unambiguously, and so they
+		// persist as JSON in the config file
+		buf, err := json.Marshal(v)
 		if err != nil {
 			Errorf(nil, "Can't encode default value for %q key - ignoring: %v", o.Name, err)
 			return "[]"
@@ -235,18 +316,22 @@ func (o *Option) String() string {
 }
 
 // Set an Option from a string
+//
+// For slice-typed options (SliceOption) s may use the +=/-=/=
+// operator syntax documented on SliceOp to append, remove, reset or
+// replace elements instead of the default append-on-set behaviour. A
+// JSON array (as produced by Option.String and persisted to the
+// config file) is recognised and loaded directly, so round-tripping
+// through the config file works for every slice type, not just
+// []string.
 func (o *Option) Set(s string) (err error) {
 	v := o.GetValue()
-	if stringArray, isStringArray := v.([]string); isStringArray {
-		if stringArray == nil {
-			stringArray = []string{}
+	if isSliceValue(v) {
+		if strings.HasPrefix(strings.TrimSpace(s), "[") {
+			return o.setSliceJSON(s)
 		}
-		// If this is still the default value then overwrite the defaults
-		if reflect.ValueOf(o.Default).Pointer() == reflect.ValueOf(v).Pointer() {
-			stringArray = []string{}
-		}
-		o.Value = append(stringArray, s)
-		return nil
+		op, value := parseSliceOp(s)
+		return o.setSlice(op, value)
 	}
 	newValue, err := configstruct.StringToInterface(v, s)
 	if err != nil {
@@ -269,11 +354,18 @@ func (o *Option) Type() string {
 		return do.Type()
 	}
 
-	// Special case []string
+	// Special case []string - kept as "stringArray" for backwards
+	// compatibility with existing config/rc consumers
 	if _, isStringArray := v.([]string); isStringArray {
 		return "stringArray"
 	}
 
+	// Other slice types report as e.g. "intSlice", "durationSlice" so
+	// the rc/config UI can tell they support SliceOp add/remove
+	if isSliceValue(v) {
+		return sliceElemTypeName(v)
+	}
+
 	return reflect.TypeOf(v).Name()
 }
 
@@ -328,7 +420,18 @@ func Register(info *RegInfo) {
 	if info.Prefix == "" {
 		info.Prefix = info.Name
 	}
+	// optDescription must be appended before validateGroups runs, so
+	// that its Advanced setting gets auto-assigned to AdvancedGroupName
+	// like every other advanced option's, instead of landing in the
+	// ungrouped "" bucket of Options.ByGroup.
 	info.Options = append(info.Options, optDescription)
+	if err := validateGroups(info.Prefix, info.Options); err != nil {
+		log.Fatalf("Failed to register %s: %v", info.Name, err)
+	}
+	if err := validateProviderSchemas(info.Prefix, info.Options, info.ProviderSchemas); err != nil {
+		log.Fatalf("Failed to register %s: %v", info.Name, err)
+	}
+	info.NewFs = wrapNewFsWithConfigFile(info.NewFs)
 	Registry = append(Registry, info)
 	for _, alias := range info.Aliases {
 		// Copy the info block and rename and hide the alias and options
@@ -0,0 +1,202 @@
+package fs
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rclone/rclone/fs/config/configmap"
+	"github.com/rclone/rclone/fs/config/configstruct"
+)
+
+// structTag is the parsed form of an `rclone:"..."` struct tag
+type structTag struct {
+	name      string
+	help      string
+	def       string
+	hasDef    bool
+	advanced  bool
+	sensitive bool
+	provider  string
+	groups    string
+	shortOpt  string
+	hide      OptionVisibility
+	isPass    bool
+	examples  OptionExamples
+}
+
+// parseStructTag parses the comma separated `key=value` and bare
+// `key` pairs found in an `rclone:"..."` struct tag, e.g.
+//
+//	`rclone:"name=chunk_size,help=Upload chunk size,default=5M,advanced,provider=AWS"`
+func parseStructTag(tag string) (structTag, error) {
+	var st structTag
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, hasValue := part, "", false
+		if i := strings.Index(part, "="); i >= 0 {
+			key, value, hasValue = part[:i], part[i+1:], true
+		}
+		switch key {
+		case "name":
+			st.name = value
+		case "help":
+			st.help = value
+		case "default":
+			st.def, st.hasDef = value, true
+		case "advanced":
+			st.advanced = true
+		case "sensitive":
+			st.sensitive = true
+		case "password":
+			st.isPass = true
+		case "provider":
+			st.provider = value
+		case "group", "groups":
+			st.groups = value
+		case "shortopt":
+			st.shortOpt = value
+		case "hide":
+			st.hide = OptionHideBoth
+		case "examples":
+			for _, example := range strings.Split(value, ";") {
+				if example == "" {
+					continue
+				}
+				exampleValue, exampleHelp, _ := strings.Cut(example, ":")
+				st.examples = append(st.examples, OptionExample{Value: exampleValue, Help: exampleHelp})
+			}
+		default:
+			if hasValue {
+				return st, fmt.Errorf("unknown rclone struct tag key %q", key)
+			}
+			return st, fmt.Errorf("unknown rclone struct tag flag %q", key)
+		}
+	}
+	return st, nil
+}
+
+// optionFromStructField parses field's `rclone:"..."` tag and builds
+// the Option it describes. Both RegisterStruct and MigrateStruct call
+// this, so they can never drift apart on which tag keys make it into
+// the resulting Option.
+//
+// ok is false if field has no rclone tag at all, in which case it is
+// silently skipped rather than being an error.
+func optionFromStructField(field reflect.StructField) (opt Option, ok bool, err error) {
+	tag, hasTag := field.Tag.Lookup("rclone")
+	if !hasTag {
+		return Option{}, false, nil
+	}
+	st, err := parseStructTag(tag)
+	if err != nil {
+		return Option{}, false, fmt.Errorf("field %s: %w", field.Name, err)
+	}
+	if st.name == "" {
+		return Option{}, false, fmt.Errorf("field %s: rclone tag missing name=", field.Name)
+	}
+
+	opt = Option{
+		Name:       st.name,
+		FieldName:  field.Name,
+		Help:       st.help,
+		Groups:     st.groups,
+		Provider:   st.provider,
+		ShortOpt:   st.shortOpt,
+		Hide:       st.hide,
+		IsPassword: st.isPass,
+		Advanced:   st.advanced,
+		Sensitive:  st.sensitive,
+		Examples:   st.examples,
+	}
+	if st.hasDef {
+		opt.Default, err = defaultForField(field, st.def)
+		if err != nil {
+			return Option{}, false, fmt.Errorf("field %s: invalid default %q: %w", field.Name, st.def, err)
+		}
+	}
+	return opt, true, nil
+}
+
+// defaultForField converts the string form of a `default=` tag value
+// into a Go value matching field's type, so Option.Default has its
+// natural type (e.g. a SizeSuffix for `default=5M`) rather than
+// always being a string. It uses the same configstruct.StringToInterface
+// helper that backs typed slice parsing in Option.Set, rather than
+// hand-rolling a parallel, partial kind switch.
+func defaultForField(field reflect.StructField, s string) (interface{}, error) {
+	zero := reflect.Zero(field.Type).Interface()
+	return configstruct.StringToInterface(zero, s)
+}
+
+// RegisterStruct derives an Options slice from the `rclone:"..."`
+// struct tags on optsPtr, a pointer to a struct, and registers info
+// with its Options populated from them.
+//
+// This lets a backend declare its options once, on the config struct
+// it already needs for NewFs, instead of hand-writing a parallel
+// Options slice kept in sync by hand, in the style of
+// jessevdk/go-flags.
+//
+// The struct fields that back each option are filled in by
+// configstruct when the returned Setter is called with the resolved
+// configmap.Getter for the remote.
+func RegisterStruct(info *RegInfo, optsPtr interface{}) (func(m configmap.Getter) error, error) {
+	v := reflect.ValueOf(optsPtr)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("RegisterStruct: optsPtr must be a pointer to a struct, got %T", optsPtr)
+	}
+	t := v.Elem().Type()
+
+	seen := map[string]bool{}
+	var opts Options
+	for i := 0; i < t.NumField(); i++ {
+		opt, ok, err := optionFromStructField(t.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("RegisterStruct: %w", err)
+		}
+		if !ok {
+			continue
+		}
+		if seen[opt.Name] {
+			return nil, fmt.Errorf("RegisterStruct: duplicate option name %q", opt.Name)
+		}
+		seen[opt.Name] = true
+		opts = append(opts, opt)
+	}
+
+	info.Options = opts
+	Register(info)
+
+	setter := func(m configmap.Getter) error {
+		return configstruct.Set(m, optsPtr)
+	}
+	return setter, nil
+}
+
+// MigrateStruct prints the Options literal equivalent to the
+// `rclone:"..."` tags on optsPtr, so an existing backend that wants
+// to move to RegisterStruct incrementally can paste the output and
+// compare it against its hand-written Options slice.
+func MigrateStruct(optsPtr interface{}) (Options, error) {
+	v := reflect.ValueOf(optsPtr)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("MigrateStruct: optsPtr must be a pointer to a struct, got %T", optsPtr)
+	}
+	t := v.Elem().Type()
+	var opts Options
+	for i := 0; i < t.NumField(); i++ {
+		opt, ok, err := optionFromStructField(t.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("MigrateStruct: %w", err)
+		}
+		if !ok {
+			continue
+		}
+		opts = append(opts, opt)
+	}
+	return opts, nil
+}
@@ -0,0 +1,89 @@
+package fs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStructTag(t *testing.T) {
+	st, err := parseStructTag("name=chunk_size,help=Upload chunk size,default=5M,advanced,provider=AWS")
+	require.NoError(t, err)
+	assert.Equal(t, "chunk_size", st.name)
+	assert.Equal(t, "Upload chunk size", st.help)
+	assert.Equal(t, "5M", st.def)
+	assert.True(t, st.hasDef)
+	assert.True(t, st.advanced)
+	assert.Equal(t, "AWS", st.provider)
+
+	_, err = parseStructTag("bogus=1")
+	assert.Error(t, err)
+}
+
+type migrateTestOpts struct {
+	ChunkSize SizeSuffix `rclone:"name=chunk_size,help=Upload chunk size,default=5M"`
+	Transfers int        `rclone:"name=transfers,help=Number of transfers,default=4"`
+}
+
+func TestMigrateStructTypedDefault(t *testing.T) {
+	opts, err := MigrateStruct(&migrateTestOpts{})
+	require.NoError(t, err)
+	require.Len(t, opts, 2)
+
+	chunkSize := opts.Get("chunk_size")
+	require.NotNil(t, chunkSize)
+	assert.Equal(t, SizeSuffix(5*1024*1024), chunkSize.Default, "default=5M must parse to a typed SizeSuffix, not the string \"5M\"")
+
+	transfers := opts.Get("transfers")
+	require.NotNil(t, transfers)
+	assert.Equal(t, 4, transfers.Default)
+}
+
+type fullTagTestOpts struct {
+	APIKey string `rclone:"name=api_key,help=API key,group=Auth,shortopt=k,hide,password,examples=foo:Use foo;bar:Use bar"`
+}
+
+// TestRegisterStructMigrateStructAgree checks that RegisterStruct and
+// MigrateStruct build identical Options for a field using group=,
+// shortopt=, hide, password and examples= tags, since both are meant
+// to derive from the same struct tags via optionFromStructField.
+func TestRegisterStructMigrateStructAgree(t *testing.T) {
+	migrated, err := MigrateStruct(&fullTagTestOpts{})
+	require.NoError(t, err)
+	require.Len(t, migrated, 1)
+
+	// Register validates that every group an option names is known
+	RegisterGroup(GroupInfo{Name: "Auth", DisplayName: "Auth"})
+	defer delete(groupRegistry, "Auth")
+
+	info := &RegInfo{Name: "fulltagtest"}
+	defer func() {
+		for i, ri := range Registry {
+			if ri == info {
+				Registry = append(Registry[:i], Registry[i+1:]...)
+				break
+			}
+		}
+	}()
+	_, err = RegisterStruct(info, &fullTagTestOpts{})
+	require.NoError(t, err)
+
+	registeredOpt := info.Options.Get("api_key")
+	require.NotNil(t, registeredOpt)
+	registered := *registeredOpt
+	assert.Equal(t, registered.Name, migrated[0].Name)
+	assert.Equal(t, registered.FieldName, migrated[0].FieldName)
+	assert.Equal(t, registered.Help, migrated[0].Help)
+	assert.Equal(t, registered.Groups, migrated[0].Groups)
+	assert.Equal(t, registered.ShortOpt, migrated[0].ShortOpt)
+	assert.Equal(t, registered.Hide, migrated[0].Hide)
+	assert.Equal(t, registered.IsPassword, migrated[0].IsPassword)
+	assert.Equal(t, registered.Examples, migrated[0].Examples)
+
+	assert.Equal(t, "Auth", registered.Groups)
+	assert.Equal(t, "k", registered.ShortOpt)
+	assert.Equal(t, OptionHideBoth, registered.Hide)
+	assert.True(t, registered.IsPassword)
+	assert.Equal(t, OptionExamples{{Value: "foo", Help: "Use foo"}, {Value: "bar", Help: "Use bar"}}, registered.Examples)
+}
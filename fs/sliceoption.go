@@ -0,0 +1,155 @@
+package fs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rclone/rclone/fs/config/configstruct"
+)
+
+// SliceOp identifies one of the operations a slice-typed Option
+// supports, either programmatically or via the --flag CLI syntax
+// below.
+type SliceOp byte
+
+// Recognised SliceOp values
+const (
+	SliceOpAppend  SliceOp = iota // --flag value or --flag +=value
+	SliceOpRemove                 // --flag -=value
+	SliceOpReset                  // --flag -=* resets to an empty slice
+	SliceOpReplace                // --flag =value1,value2 replaces the whole slice
+)
+
+// parseSliceOp splits the raw string passed to Option.Set into the
+// SliceOp it requests and the value(s) that go with it.
+//
+// A bare value with no prefix is an append, matching the historical
+// behaviour of repeating a flag on the command line to build up a
+// slice.
+//
+// A leading backslash escapes this parsing: "\+=foo" is the literal
+// append value "+=foo" rather than the append operator applied to
+// "foo". This is the only way to set a value that legitimately starts
+// with "+=", "-=" or "=" (a path, header or filter value, say)
+// without it being misread as an operator.
+func parseSliceOp(s string) (op SliceOp, value string) {
+	if strings.HasPrefix(s, `\`) {
+		return SliceOpAppend, s[1:]
+	}
+	switch {
+	case strings.HasPrefix(s, "+="):
+		return SliceOpAppend, s[2:]
+	case s == "-=*":
+		return SliceOpReset, ""
+	case strings.HasPrefix(s, "-="):
+		return SliceOpRemove, s[2:]
+	case strings.HasPrefix(s, "="):
+		return SliceOpReplace, s[1:]
+	}
+	return SliceOpAppend, s
+}
+
+// isSliceValue reports whether v is a slice type supported by the
+// SliceOption append/remove/reset/replace semantics. []byte is
+// excluded since that is normally treated as a scalar blob, not a
+// list of options.
+func isSliceValue(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+	t := reflect.TypeOf(v)
+	return t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8
+}
+
+// sliceElemTypeName returns the Option.Type() name for a slice value,
+// e.g. "stringSlice" for []string, "intSlice" for []int, "durationSlice"
+// for []Duration - always lowerCamelCase regardless of how the
+// element type itself is capitalised.
+func sliceElemTypeName(v interface{}) string {
+	t := reflect.TypeOf(v)
+	elemName := t.Elem().Name()
+	if elemName == "" {
+		elemName = t.Elem().String()
+	}
+	r, size := utf8.DecodeRuneInString(elemName)
+	elemName = string(unicode.ToLower(r)) + elemName[size:]
+	return elemName + "Slice"
+}
+
+// setSliceJSON loads a JSON array (the form produced by Option.String
+// for every slice type) directly into o.Value, replacing whatever was
+// there before. This is what makes a slice-typed option round-trip
+// through the config file.
+func (o *Option) setSliceJSON(s string) error {
+	cur := reflect.ValueOf(o.GetValue())
+	out := reflect.New(cur.Type())
+	if err := json.Unmarshal([]byte(s), out.Interface()); err != nil {
+		return fmt.Errorf("failed to parse %q as a %s: %w", s, cur.Type(), err)
+	}
+	o.Value = out.Elem().Interface()
+	return nil
+}
+
+// setSlice applies op to the slice held in o.Value (starting from
+// o.Default if Value hasn't been set yet), parsing value with
+// configstruct so typed slices (ints, SizeSuffix, Duration, ...)
+// work the same way as []string.
+func (o *Option) setSlice(op SliceOp, value string) error {
+	cur := reflect.ValueOf(o.GetValue())
+
+	parseElem := func(s string) (reflect.Value, error) {
+		parsed, err := configstruct.StringToInterface(reflect.Zero(cur.Type().Elem()).Interface(), s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(parsed), nil
+	}
+
+	switch op {
+	case SliceOpReset:
+		o.Value = reflect.MakeSlice(cur.Type(), 0, 0).Interface()
+		return nil
+	case SliceOpReplace:
+		out := reflect.MakeSlice(cur.Type(), 0, 0)
+		if value != "" {
+			for _, s := range strings.Split(value, ",") {
+				elem, err := parseElem(s)
+				if err != nil {
+					return err
+				}
+				out = reflect.Append(out, elem)
+			}
+		}
+		o.Value = out.Interface()
+		return nil
+	case SliceOpAppend:
+		// If this is still the default value then overwrite the
+		// defaults, as a fresh slice built from CLI/config input
+		// rather than appending on top of the backend's defaults.
+		if def := reflect.ValueOf(o.Default); def.IsValid() && def.Kind() == reflect.Slice && def.Pointer() == cur.Pointer() {
+			cur = reflect.MakeSlice(cur.Type(), 0, 0)
+		}
+		elem, err := parseElem(value)
+		if err != nil {
+			return err
+		}
+		o.Value = reflect.Append(cur, elem).Interface()
+		return nil
+	case SliceOpRemove:
+		out := reflect.MakeSlice(cur.Type(), 0, 0)
+		for i := 0; i < cur.Len(); i++ {
+			item := cur.Index(i)
+			if fmt.Sprint(item.Interface()) == value {
+				continue
+			}
+			out = reflect.Append(out, item)
+		}
+		o.Value = out.Interface()
+		return nil
+	}
+	return fmt.Errorf("unknown slice operation %v", op)
+}
@@ -0,0 +1,79 @@
+package fs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSliceElemTypeName(t *testing.T) {
+	for _, test := range []struct {
+		value interface{}
+		want  string
+	}{
+		{[]string{}, "stringSlice"},
+		{[]int{}, "intSlice"},
+		{[]Duration{}, "durationSlice"},
+		{[]SizeSuffix{}, "sizeSuffixSlice"},
+	} {
+		got := sliceElemTypeName(test.value)
+		assert.Equal(t, test.want, got, "%T", test.value)
+	}
+}
+
+func TestOptionStringJSONRoundTrip(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"empty string slice", []string{}, ""},
+		{"string slice", []string{"a", "b"}, `["a","b"]`},
+		{"int slice", []int{1, 2, 3}, `[1,2,3]`},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			o := &Option{Name: test.name, Default: test.value}
+			assert.Equal(t, test.want, o.String())
+			if test.want == "" {
+				return
+			}
+			// round trip: Set should read back exactly what String produced
+			o2 := &Option{Name: test.name, Default: test.value}
+			require.NoError(t, o2.Set(o.String()))
+			assert.Equal(t, test.value, o2.Value)
+		})
+	}
+}
+
+func TestOptionSetSliceOps(t *testing.T) {
+	o := &Option{Name: "test", Default: []string{"a"}}
+
+	require.NoError(t, o.Set("b"))
+	assert.Equal(t, []string{"b"}, o.Value, "append on the default value starts fresh")
+
+	require.NoError(t, o.Set("+=c"))
+	assert.Equal(t, []string{"b", "c"}, o.Value)
+
+	require.NoError(t, o.Set("-=b"))
+	assert.Equal(t, []string{"c"}, o.Value)
+
+	require.NoError(t, o.Set("=x,y,z"))
+	assert.Equal(t, []string{"x", "y", "z"}, o.Value)
+
+	require.NoError(t, o.Set("-=*"))
+	assert.Equal(t, []string{}, o.Value)
+}
+
+func TestOptionSetSliceLiteralEscape(t *testing.T) {
+	o := &Option{Name: "test", Default: []string{}}
+
+	require.NoError(t, o.Set(`\+=literal`))
+	assert.Equal(t, []string{"+=literal"}, o.Value, "a backslash-escaped value starting with += must be appended literally, not parsed as the append operator")
+
+	require.NoError(t, o.Set(`\-=literal`))
+	assert.Equal(t, []string{"+=literal", "-=literal"}, o.Value)
+
+	require.NoError(t, o.Set(`\=literal`))
+	assert.Equal(t, []string{"+=literal", "-=literal", "=literal"}, o.Value)
+}